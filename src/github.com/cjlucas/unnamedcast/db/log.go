@@ -1,6 +1,9 @@
 package db
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Log struct {
 	ID            ID                  `bson:"_id,omitempty" json:"id"`
@@ -26,8 +29,8 @@ func (c LogCollection) LogByID(id ID) (*Log, error) {
 	return &log, nil
 }
 
-func (c LogCollection) Create(log *Log) error {
+func (c LogCollection) Create(ctx context.Context, log *Log) error {
 	log.ID = NewID()
 	log.CreationTime = time.Now().UTC()
-	return c.insert(log)
+	return c.WithContext(ctx).insert(log)
 }
\ No newline at end of file
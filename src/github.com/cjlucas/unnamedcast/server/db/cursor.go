@@ -0,0 +1,60 @@
+package db
+
+import "gopkg.in/mgo.v2"
+
+// Cursor represents a query result that hasn't been executed against Mongo
+// yet. Operations are deferred until One, All, or Count is called.
+type Cursor interface {
+	One(result interface{}) error
+	All(result interface{}) error
+	Count() (int, error)
+	Select(fields interface{})
+	Sort(fields ...string)
+	Limit(n int)
+	Hint(fields ...string)
+}
+
+// query carries the release func for the session its mgo.Query runs on, so
+// the session (and the goroutine watching it, see
+// collection.sessionForContext) is freed as soon as the query executes
+// instead of only when its context is cancelled.
+type query struct {
+	release func()
+	q       *mgo.Query
+}
+
+func (c *query) One(result interface{}) error {
+	defer c.release()
+	return c.q.One(result)
+}
+
+func (c *query) All(result interface{}) error {
+	defer c.release()
+	return c.q.All(result)
+}
+
+func (c *query) Count() (int, error) {
+	defer c.release()
+	return c.q.Count()
+}
+
+func (c *query) Select(fields interface{}) { c.q.Select(fields) }
+func (c *query) Sort(fields ...string)     { c.q.Sort(fields...) }
+func (c *query) Limit(n int)               { c.q.Limit(n) }
+func (c *query) Hint(fields ...string)     { c.q.Hint(fields...) }
+
+// Pipe wraps an aggregation pipeline the same way query wraps a find.
+type Pipe struct {
+	release func()
+	p       *mgo.Pipe
+}
+
+func (p *Pipe) One(result interface{}) error {
+	defer p.release()
+	return p.p.One(result)
+}
+
+func (p *Pipe) All(result interface{}) error {
+	defer p.release()
+	return p.p.All(result)
+}
@@ -0,0 +1,31 @@
+package db
+
+import "gopkg.in/mgo.v2/bson"
+
+// Feed represents a podcast feed.
+type Feed struct {
+	ID          bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	URL         string        `bson:"url" json:"url" index:"url,unique"`
+	Title       string        `bson:"title" json:"title" index:",text"`
+	Author      string        `bson:"author" json:"author" index:",text"`
+	Description string        `bson:"description" json:"description" index:",text"`
+}
+
+type FeedCollection struct {
+	collection
+}
+
+// Search performs a full-text search against the feed's title, author, and
+// description fields, returning up to limit results ranked by relevance.
+func (c FeedCollection) Search(term string, limit int) ([]Feed, error) {
+	var feeds []Feed
+	q := &Query{
+		TextSearch:    term,
+		TextScoreSort: true,
+		Limit:         limit,
+	}
+	if err := c.Find(q).All(&feeds); err != nil {
+		return nil, err
+	}
+	return feeds, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// errCursor is a Cursor that fails every operation with err, used to make
+// an invalid index lookup (unknown name, wrong value count) fail
+// immediately instead of building an incorrect filter.
+type errCursor struct{ err error }
+
+func (c errCursor) One(result interface{}) error { return c.err }
+func (c errCursor) All(result interface{}) error { return c.err }
+func (c errCursor) Count() (int, error)          { return 0, c.err }
+func (c errCursor) Select(fields interface{})    {}
+func (c errCursor) Sort(fields ...string)        {}
+func (c errCursor) Limit(n int)                  {}
+func (c errCursor) Hint(fields ...string)        {}
+
+// ByIndex validates values against the named index's Key length and
+// returns a cursor hinted to use that index, built from an equality filter
+// over values in Key order. An unknown index name or a value count
+// mismatch returns an errCursor immediately, rather than a wrong-field
+// lookup silently falling back to a full collection scan.
+func (c collection) ByIndex(name string, values ...interface{}) Cursor {
+	idx, ok := c.ModelInfo.Indexes[name]
+	if !ok {
+		return errCursor{fmt.Errorf("db: no index named %q declared on %T", name, c.c)}
+	}
+	if len(values) != len(idx.Key) {
+		return errCursor{fmt.Errorf("db: index %q has %d key(s), got %d value(s)", name, len(idx.Key), len(values))}
+	}
+
+	filter := make(bson.M, len(idx.Key))
+	for i, k := range idx.Key {
+		filter[k] = values[i]
+	}
+
+	cur := c.Find(&Query{Filter: filter})
+	cur.Hint(idx.Key...)
+	return cur
+}
+
+// RangeByIndex returns a cursor over documents whose value for the named
+// single-key index falls within [low, high] (or (low, high) when inclusive
+// is false), ordered by that key and hinted to use it.
+func (c collection) RangeByIndex(name string, low, high interface{}, inclusive bool) Cursor {
+	idx, ok := c.ModelInfo.Indexes[name]
+	if !ok {
+		return errCursor{fmt.Errorf("db: no index named %q declared on %T", name, c.c)}
+	}
+	if len(idx.Key) != 1 {
+		return errCursor{fmt.Errorf("db: index %q is not a single-key index", name)}
+	}
+
+	gte, lte := "$gte", "$lte"
+	if !inclusive {
+		gte, lte = "$gt", "$lt"
+	}
+
+	key := idx.Key[0]
+	cur := c.Find(&Query{
+		Filter:    bson.M{key: bson.M{gte: low, lte: high}},
+		SortField: key,
+	})
+	cur.Hint(key)
+	return cur
+}
+
+// UniqueLookup performs an equality lookup against the named unique index
+// and reports false (with a nil error) when nothing matches. It fails
+// immediately if the index isn't declared unique, since a non-unique index
+// can't guarantee the single-result semantics callers expect.
+func (c collection) UniqueLookup(name string, value interface{}) (bson.Raw, bool, error) {
+	idx, ok := c.ModelInfo.Indexes[name]
+	if !ok {
+		return bson.Raw{}, false, fmt.Errorf("db: no index named %q declared on %T", name, c.c)
+	}
+	if !idx.Unique {
+		return bson.Raw{}, false, fmt.Errorf("db: index %q is not unique", name)
+	}
+	if len(idx.Key) != 1 {
+		return bson.Raw{}, false, fmt.Errorf("db: index %q is not a single-key index", name)
+	}
+
+	var raw bson.Raw
+	err := c.Find(&Query{Filter: bson.M{idx.Key[0]: value}}).One(&raw)
+	switch err {
+	case nil:
+		return raw, true, nil
+	case mgo.ErrNotFound:
+		return bson.Raw{}, false, nil
+	default:
+		return bson.Raw{}, false, err
+	}
+}
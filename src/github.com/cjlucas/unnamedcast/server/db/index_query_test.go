@@ -0,0 +1,80 @@
+package db
+
+import "testing"
+
+func testCollection() collection {
+	return collection{
+		ModelInfo: ModelInfo{
+			Indexes: map[string]Index{
+				"url":  {Name: "url", Key: []string{"url"}, Unique: true},
+				"name": {Name: "name", Key: []string{"first", "last"}},
+			},
+		},
+	}
+}
+
+func errFromCursor(t *testing.T, cur Cursor) error {
+	t.Helper()
+	return cur.One(nil)
+}
+
+func TestByIndex_UnknownIndex(t *testing.T) {
+	c := testCollection()
+	if err := errFromCursor(t, c.ByIndex("nope", "x")); err == nil {
+		t.Fatal("expected an error for an unknown index name")
+	}
+}
+
+func TestByIndex_WrongValueCount(t *testing.T) {
+	c := testCollection()
+	if err := errFromCursor(t, c.ByIndex("name", "only-one")); err == nil {
+		t.Fatal("expected an error when value count doesn't match the index's key length")
+	}
+}
+
+func TestRangeByIndex_UnknownIndex(t *testing.T) {
+	c := testCollection()
+	if err := errFromCursor(t, c.RangeByIndex("nope", 0, 10, true)); err == nil {
+		t.Fatal("expected an error for an unknown index name")
+	}
+}
+
+func TestRangeByIndex_MultiKeyIndex(t *testing.T) {
+	c := testCollection()
+	if err := errFromCursor(t, c.RangeByIndex("name", "a", "z", true)); err == nil {
+		t.Fatal("expected an error for a non-single-key index")
+	}
+}
+
+func TestUniqueLookup_UnknownIndex(t *testing.T) {
+	c := testCollection()
+	if _, _, err := c.UniqueLookup("nope", "x"); err == nil {
+		t.Fatal("expected an error for an unknown index name")
+	}
+}
+
+func TestUniqueLookup_NotUnique(t *testing.T) {
+	c := collection{
+		ModelInfo: ModelInfo{
+			Indexes: map[string]Index{
+				"first": {Name: "first", Key: []string{"first"}},
+			},
+		},
+	}
+	if _, _, err := c.UniqueLookup("first", "x"); err == nil {
+		t.Fatal("expected an error looking up a non-unique index")
+	}
+}
+
+func TestUniqueLookup_MultiKeyIndex(t *testing.T) {
+	c := collection{
+		ModelInfo: ModelInfo{
+			Indexes: map[string]Index{
+				"name": {Name: "name", Key: []string{"first", "last"}, Unique: true},
+			},
+		},
+	}
+	if _, _, err := c.UniqueLookup("name", "x"); err == nil {
+		t.Fatal("expected an error looking up a unique index with more than one key field")
+	}
+}
@@ -1,9 +1,12 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -63,6 +66,48 @@ type Query struct {
 	SelectedFields []string
 	OmittedFields  []string
 	Limit          int
+
+	// TextSearch performs a $text search against the collection's text
+	// index (see Index.Text). TextScoreSort sorts and projects results by
+	// their $meta textScore instead of SortField.
+	TextSearch    string
+	TextScoreSort bool
+
+	// MaxTimeMS caps how long the server will spend executing the query,
+	// via mgo's SetMaxTime. Zero means no server-side cap.
+	MaxTimeMS int
+}
+
+// Index describes a Mongo index built from fields tagged with `index:"..."`.
+// Fields tagged `index:",text"` are all folded into a single compound text
+// index, since Mongo only allows one per collection.
+type Index struct {
+	Name   string
+	Key    []string
+	Unique bool
+	Text   bool
+}
+
+// textIndexName is the name given to the single compound text index built
+// from every field tagged `index:",text"`.
+const textIndexName = "text"
+
+// mgoIndexForIndex translates an Index into the mgo.Index Mongo expects,
+// prefixing keys with "$text:" for text indexes per mgo's convention.
+func mgoIndexForIndex(idx Index) mgo.Index {
+	key := idx.Key
+	if idx.Text {
+		key = make([]string, len(idx.Key))
+		for i, k := range idx.Key {
+			key[i] = "$text:" + k
+		}
+	}
+
+	return mgo.Index{
+		Name:   idx.Name,
+		Key:    key,
+		Unique: idx.Unique,
+	}
 }
 
 type FieldInfo struct {
@@ -133,9 +178,8 @@ type ModelInfo struct {
 	jsonNameMap map[string]int
 	bsonNameMap map[string]int
 
-	// Indexed colums? (could allow index creation to be moved to)
-	// Add ability to delegate index creation/rebuilding/deleting to collection
-	// Instead of in app setup
+	// Indexes declared via `index:"..."` tags. See collection.SyncIndexes
+	// for how these are reconciled against what's actually in Mongo.
 	Indexes map[string]Index
 }
 
@@ -178,16 +222,21 @@ func newModelInfo(m interface{}) ModelInfo {
 
 		info.addField(tag)
 
-		if tag.IndexName != "" {
-			if idx, ok := info.Indexes[tag.IndexName]; ok {
-				idx.Key = append(idx.Key, tag.BSONName)
-			} else {
-				info.Indexes[tag.IndexName] = Index{
-					Name:   tag.IndexName,
-					Key:    []string{tag.BSONName},
-					Unique: tag.IndexUnique,
-				}
+		switch {
+		case tag.IndexText:
+			idx, ok := info.Indexes[textIndexName]
+			if !ok {
+				idx = Index{Name: textIndexName, Text: true}
+			}
+			idx.Key = append(idx.Key, tag.BSONName)
+			info.Indexes[textIndexName] = idx
+		case tag.IndexName != "":
+			idx, ok := info.Indexes[tag.IndexName]
+			if !ok {
+				idx = Index{Name: tag.IndexName, Unique: tag.IndexUnique}
 			}
+			idx.Key = append(idx.Key, tag.BSONName)
+			info.Indexes[tag.IndexName] = idx
 		}
 	}
 
@@ -197,33 +246,93 @@ func newModelInfo(m interface{}) ModelInfo {
 type collection struct {
 	c         *mgo.Collection
 	ModelInfo ModelInfo
+	ctx       context.Context
+}
+
+// WithContext returns a copy of the collection whose operations are bound
+// to ctx, so a caller can cancel or set a deadline on a single request
+// without it affecting other callers sharing the same collection.
+func (c collection) WithContext(ctx context.Context) collection {
+	c.ctx = ctx
+	return c
+}
+
+// sessionForContext returns the session operations should run on, along
+// with a release func the caller must invoke exactly once when it's done
+// with that session. When the collection carries a context (via
+// WithContext), a copy of the underlying session is made; release closes
+// it as soon as the caller is finished, and a background goroutine races
+// that against the context being done, closing the copy early to unblock
+// whatever socket read or write is in flight on it — the same trick
+// gonet's deadline timer uses to cancel a blocked read. Either way the
+// goroutine exits and the session is released, so a context that's never
+// cancelled (e.g. context.Background() handed to db.SyncAll) doesn't leak
+// either of them.
+func (c collection) sessionForContext() (s *mgo.Session, release func()) {
+	if c.ctx == nil {
+		return c.c.Database.Session, func() {}
+	}
+
+	s = c.c.Database.Session.Copy()
+	done := make(chan struct{})
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			close(done)
+			s.Close()
+		})
+	}
+
+	go func() {
+		select {
+		case <-c.ctx.Done():
+			s.Close()
+		case <-done:
+		}
+	}()
+
+	return s, release
 }
 
 func (c collection) Find(q *Query) Cursor {
+	s, release := c.sessionForContext()
+	mc := c.c.With(s)
+
 	if q == nil {
-		return &query{
-			s: c.c.Database.Session,
-			q: c.c.Find(nil),
-		}
+		return &query{release: release, q: mc.Find(nil)}
 	}
 
-	cur := &query{
-		s: c.c.Database.Session,
-		q: c.c.Find(q.Filter),
+	filter := q.Filter
+	if q.TextSearch != "" {
+		if filter == nil {
+			filter = bson.M{}
+		}
+		filter["$text"] = bson.M{"$search": q.TextSearch}
 	}
 
-	sel := make(map[string]int)
-	for _, s := range q.SelectedFields {
-		sel[s] = 1
-	}
-	for _, s := range q.OmittedFields {
-		sel[s] = -1
+	cur := &query{release: release, q: mc.Find(filter)}
+	if q.MaxTimeMS > 0 {
+		cur.q.SetMaxTime(time.Duration(q.MaxTimeMS) * time.Millisecond)
 	}
-	if len(sel) > 0 {
-		cur.Select(sel)
+
+	if q.TextScoreSort {
+		cur.Select(bson.M{"score": bson.M{"$meta": "textScore"}})
+	} else {
+		sel := make(map[string]int)
+		for _, s := range q.SelectedFields {
+			sel[s] = 1
+		}
+		for _, s := range q.OmittedFields {
+			sel[s] = -1
+		}
+		if len(sel) > 0 {
+			cur.Select(sel)
+		}
 	}
 
-	if q.SortField != "" {
+	if q.TextScoreSort {
+		cur.Sort("$textScore:score")
+	} else if q.SortField != "" {
 		sortField := q.SortField
 		if q.SortDesc {
 			sortField = "-" + sortField
@@ -239,23 +348,23 @@ func (c collection) Find(q *Query) Cursor {
 }
 
 func (c collection) FindByID(id bson.ObjectId) Cursor {
-	return &query{
-		s: c.c.Database.Session,
-		q: c.c.FindId(id),
-	}
+	s, release := c.sessionForContext()
+	return &query{release: release, q: c.c.With(s).FindId(id)}
 }
 
 func (c collection) EnsureIndex(idx Index) error {
-	return c.c.EnsureIndex(mgoIndexForIndex(idx))
+	s, release := c.sessionForContext()
+	defer release()
+	return c.c.With(s).EnsureIndex(mgoIndexForIndex(idx))
 }
 
 func (c collection) insert(model interface{}) error {
-	return c.c.Insert(model)
+	s, release := c.sessionForContext()
+	defer release()
+	return c.c.With(s).Insert(model)
 }
 
 func (c collection) pipeline(pipeline interface{}) *Pipe {
-	return &Pipe{
-		s: c.c.Database.Session,
-		p: c.c.Pipe(pipeline),
-	}
+	s, release := c.sessionForContext()
+	return &Pipe{release: release, p: c.c.With(s).Pipe(pipeline)}
 }
@@ -0,0 +1,47 @@
+package db
+
+import "testing"
+
+type textTagModel struct {
+	ID     string `json:"id" bson:"_id"`
+	Title  string `json:"title" bson:"title" index:",text"`
+	Author string `json:"author" bson:"author" index:",text"`
+	URL    string `json:"url" bson:"url" index:"url,unique"`
+}
+
+func TestNewModelInfo_TextFieldsShareOneIndex(t *testing.T) {
+	info := newModelInfo(textTagModel{})
+
+	idx, ok := info.Indexes[textIndexName]
+	if !ok {
+		t.Fatalf("expected a %q index to be built from the text-tagged fields", textIndexName)
+	}
+	if !idx.Text {
+		t.Errorf("expected %q index to be marked Text", textIndexName)
+	}
+
+	want := map[string]bool{"title": true, "author": true}
+	if len(idx.Key) != len(want) {
+		t.Fatalf("expected text index to cover %d fields, got %v", len(want), idx.Key)
+	}
+	for _, k := range idx.Key {
+		if !want[k] {
+			t.Errorf("unexpected field %q in text index key", k)
+		}
+	}
+}
+
+func TestNewModelInfo_NonTextIndexUnaffected(t *testing.T) {
+	info := newModelInfo(textTagModel{})
+
+	idx, ok := info.Indexes["url"]
+	if !ok {
+		t.Fatal("expected a \"url\" index to be built from the url field's tag")
+	}
+	if !idx.Unique {
+		t.Error("expected \"url\" index to be unique")
+	}
+	if len(idx.Key) != 1 || idx.Key[0] != "url" {
+		t.Errorf("expected \"url\" index key to be [\"url\"], got %v", idx.Key)
+	}
+}
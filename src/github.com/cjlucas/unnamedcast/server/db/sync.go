@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gopkg.in/mgo.v2"
+)
+
+// SyncIndexes reconciles the indexes declared on the collection's model
+// (ModelInfo.Indexes) against the indexes that actually exist in Mongo.
+// Indexes no longer declared are dropped, missing ones are created, and any
+// whose key or options (unique/text) have drifted are rebuilt. added and
+// dropped report the names of indexes that changed.
+func (c collection) SyncIndexes(ctx context.Context) (added, dropped []string, err error) {
+	c = c.WithContext(ctx)
+
+	// Acquire one session for the whole reconciliation instead of letting
+	// each sub-operation below grab its own copy.
+	s, release := c.sessionForContext()
+	defer release()
+	mc := c.c.With(s)
+
+	live, err := mc.Indexes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	liveByName := make(map[string]mgo.Index, len(live))
+	for _, idx := range live {
+		liveByName[idx.Name] = idx
+	}
+
+	for name, idx := range c.ModelInfo.Indexes {
+		have, ok := liveByName[name]
+		delete(liveByName, name)
+
+		switch {
+		case !ok:
+			if err := mc.EnsureIndex(mgoIndexForIndex(idx)); err != nil {
+				return added, dropped, err
+			}
+			added = append(added, name)
+		case !indexesEqual(have, mgoIndexForIndex(idx)):
+			if err := rebuildIndexOn(mc, idx); err != nil {
+				return added, dropped, err
+			}
+			added = append(added, name)
+		}
+	}
+
+	for name := range liveByName {
+		if name == "_id_" {
+			continue
+		}
+		if err := mc.DropIndexName(name); err != nil {
+			return added, dropped, err
+		}
+		dropped = append(dropped, name)
+	}
+
+	return added, dropped, nil
+}
+
+// rebuildIndexOn drops and recreates idx on mc, an *mgo.Collection already
+// bound to the session the caller wants the operation to run on.
+func rebuildIndexOn(mc *mgo.Collection, idx Index) error {
+	if err := mc.DropIndexName(idx.Name); err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+	return mc.EnsureIndex(mgoIndexForIndex(idx))
+}
+
+func indexesEqual(have, want mgo.Index) bool {
+	if have.Unique != want.Unique || len(have.Key) != len(want.Key) {
+		return false
+	}
+	for i := range have.Key {
+		if have.Key[i] != want.Key[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// DropIndex drops the named index from the collection.
+func (c collection) DropIndex(name string) error {
+	s, release := c.sessionForContext()
+	defer release()
+	return c.c.With(s).DropIndexName(name)
+}
+
+// RebuildIndex drops and recreates the named index using its current
+// declaration in ModelInfo.Indexes.
+func (c collection) RebuildIndex(name string) error {
+	idx, ok := c.ModelInfo.Indexes[name]
+	if !ok {
+		return fmt.Errorf("db: no index named %q declared on %T", name, c.c)
+	}
+
+	s, release := c.sessionForContext()
+	defer release()
+	return rebuildIndexOn(c.c.With(s), idx)
+}
+
+// syncer is implemented by any collection wrapper that embeds collection,
+// so a LogCollection, FeedCollection, etc. can be passed to SyncAll
+// directly.
+type syncer interface {
+	SyncIndexes(ctx context.Context) (added, dropped []string, err error)
+}
+
+// SyncAll reconciles indexes for every given collection against their
+// declared ModelInfo, so a single startup call replaces per-collection
+// index setup in app code. It logs what changed on each collection so ops
+// can see the diff on deploy.
+func SyncAll(ctx context.Context, collections ...syncer) error {
+	for _, c := range collections {
+		added, dropped, err := c.SyncIndexes(ctx)
+		if err != nil {
+			return err
+		}
+		if len(added) > 0 || len(dropped) > 0 {
+			log.Printf("db: synced indexes on %T: added=%v dropped=%v", c, added, dropped)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package db
+
+import (
+	"testing"
+
+	"gopkg.in/mgo.v2"
+)
+
+func TestIndexesEqual(t *testing.T) {
+	cases := []struct {
+		name       string
+		have, want mgo.Index
+		equal      bool
+	}{
+		{"identical", mgo.Index{Key: []string{"a"}}, mgo.Index{Key: []string{"a"}}, true},
+		{"different key", mgo.Index{Key: []string{"a"}}, mgo.Index{Key: []string{"b"}}, false},
+		{"different length", mgo.Index{Key: []string{"a"}}, mgo.Index{Key: []string{"a", "b"}}, false},
+		{"different unique", mgo.Index{Key: []string{"a"}, Unique: true}, mgo.Index{Key: []string{"a"}}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := indexesEqual(c.have, c.want); got != c.equal {
+				t.Errorf("indexesEqual(%+v, %+v) = %v, want %v", c.have, c.want, got, c.equal)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Package api contains the data types exchanged with the unnamedcast API
+// server.
+package api
+
+import "errors"
+
+// ErrNotFound is returned by lookups (e.g. FeedByURL) that find nothing
+// matching, so callers can distinguish "doesn't exist yet" from a real
+// error.
+var ErrNotFound = errors.New("api: not found")
+
+// Feed represents a podcast feed as understood by the unnamedcast API.
+type Feed struct {
+	ID    string `json:"id"`
+	URL   string `json:"url"`
+	Items []Item `json:"items"`
+}
+
+// Item represents a single episode within a Feed.
+type Item struct {
+	GUID  string `json:"guid"`
+	Title string `json:"title"`
+}
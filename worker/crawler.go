@@ -0,0 +1,68 @@
+package main
+
+import (
+	"time"
+
+	"github.com/cjlucas/unnamedcast/worker/itunes"
+	"github.com/cjlucas/unnamedcast/worker/queue"
+)
+
+// resolveFeedTaskTTL bounds how long an itunes:resolve-feed task is
+// deduplicated for, so the same feed URL discovered by two crawls of
+// overlapping pages is only resolved once.
+const resolveFeedTaskTTL = 24 * time.Hour
+
+type crawlGenrePayload struct {
+	URL string `json:"url"`
+}
+
+type resolveFeedPayload struct {
+	URL string `json:"url"`
+}
+
+// crawlGenre lists the feeds and further pagination links on a single
+// iTunes feed list page, enqueuing an itunes:resolve-feed task per feed URL
+// and an itunes:crawl-genre task per pagination link, rather than resolving
+// feed URLs inline on the crawling goroutine.
+func crawlGenre(q *queue.Client, pageURL string) error {
+	page, err := itunes.NewFeedListPage(pageURL)
+	if err != nil {
+		return err
+	}
+
+	for _, feedURL := range page.FeedURLs() {
+		payload := resolveFeedPayload{URL: feedURL}
+		opts := []queue.Option{queue.Unique(resolveFeedTaskTTL)}
+		if _, err := q.Enqueue(queue.TypeItunesResolveFeed, payload, opts...); err != nil {
+			return err
+		}
+	}
+
+	for _, nextPageURL := range page.PaginationPageList() {
+		payload := crawlGenrePayload{URL: nextPageURL}
+		if _, err := q.Enqueue(queue.TypeItunesCrawlGenre, payload, queue.Queue(queue.QueueCrawl)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateTaskTTL bounds how long a feed:update task is deduplicated for, so
+// two resolved feed URLs that point at the same RSS feed don't both
+// trigger a crawl.
+const updateTaskTTL = time.Hour
+
+// resolveFeed resolves an iTunes lookup page URL to the feed's actual RSS
+// URL and enqueues a feed:update task for it.
+func resolveFeed(q *queue.Client, pageURL string) error {
+	feedURL, err := itunes.ResolveiTunesFeedURL(pageURL)
+	if err != nil {
+		return err
+	}
+
+	payload := updateFeedPayload{URL: feedURL}
+	opts := []queue.Option{queue.Unique(updateTaskTTL), queue.Queue(queue.QueueCrawl)}
+	_, err = q.Enqueue(queue.TypeFeedUpdate, payload, opts...)
+	return err
+}
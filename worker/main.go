@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/cjlucas/unnamedcast/worker/queue"
+	"github.com/hibiken/asynq"
+)
+
+// redisConnOpt builds the Redis connection options the queue client, server,
+// and inspector all connect with, from REDIS_ADDR (defaulting to a local
+// Redis for development).
+func redisConnOpt() asynq.RedisConnOpt {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1:6379"
+	}
+	return asynq.RedisClientOpt{Addr: addr}
+}
+
+func main() {
+	redis := redisConnOpt()
+
+	client := queue.NewClient(redis)
+	defer client.Close()
+
+	server := queue.NewServer(redis, map[string]int{
+		queue.QueueCritical: 6,
+		queue.QueueDefault:  3,
+		queue.QueueCrawl:    1,
+	})
+
+	// TODO: construct Source from a real unnamedcast API client once one
+	// exists in this tree; until then feed:update/feed:merge tasks will
+	// fail on the nil Source, and the periodic refresh scheduler (which
+	// needs Source.Feeds) doesn't start.
+	worker := UpdateFeedWorker{}
+	RegisterHandlers(server, client, worker)
+
+	if worker.Source != nil {
+		go runRefreshScheduler(client, worker.Source)
+	}
+
+	go serveAdmin(redis)
+
+	log.Fatal(server.Run())
+}
+
+// serveAdmin mounts queue.AdminHandler alongside the user-triggered refresh
+// endpoint, so operators and clients share one admin listener rather than
+// each needing their own.
+func serveAdmin(redis asynq.RedisConnOpt) {
+	client := queue.NewClient(redis)
+	defer client.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle("/queues", queue.AdminHandler(queue.NewInspector(redis)))
+	mux.HandleFunc("/feeds/refresh", refreshHandler(client))
+
+	addr := os.Getenv("ADMIN_ADDR")
+	if addr == "" {
+		addr = ":8081"
+	}
+
+	log.Printf("worker: admin endpoint listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
@@ -0,0 +1,48 @@
+package queue
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hibiken/asynq"
+)
+
+// QueueStats summarizes the depth and failure count of a single queue.
+type QueueStats struct {
+	Queue   string `json:"queue"`
+	Size    int    `json:"size"`
+	Pending int    `json:"pending"`
+	Failed  int    `json:"failed"`
+}
+
+// AdminHandler serves queue depth/failure stats pulled from asynq's
+// inspector, so operators can check on crawling progress without shelling
+// into Redis.
+func AdminHandler(inspector *asynq.Inspector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		names, err := inspector.Queues()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		stats := make([]QueueStats, 0, len(names))
+		for _, name := range names {
+			info, err := inspector.GetQueueInfo(name)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			stats = append(stats, QueueStats{
+				Queue:   name,
+				Size:    info.Size,
+				Pending: info.Pending,
+				Failed:  info.Failed,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+	})
+}
@@ -0,0 +1,98 @@
+// Package queue provides a Redis-backed task queue for the worker, built on
+// top of github.com/hibiken/asynq. It replaces the in-process invocation of
+// workers like UpdateFeedWorker with enqueued tasks that a Server processes
+// from one or more priority queues.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hibiken/asynq"
+)
+
+// Task type names registered on a Server's mux.
+const (
+	TypeFeedUpdate        = "feed:update"
+	TypeFeedMerge         = "feed:merge"
+	TypeItunesCrawlGenre  = "itunes:crawl-genre"
+	TypeItunesResolveFeed = "itunes:resolve-feed"
+)
+
+// Queue priority names used when configuring a Server.
+const (
+	QueueCritical = "critical" // user-triggered work
+	QueueDefault  = "default"
+	QueueCrawl    = "crawl" // background iTunes crawling
+)
+
+// Option configures how a task is enqueued (retry count, delay, uniqueness,
+// queue, ...). It's a thin alias over asynq.Option so callers don't need to
+// import asynq directly.
+type Option = asynq.Option
+
+// Re-exported asynq.Option constructors, aliased so callers build options
+// without importing asynq directly.
+var (
+	MaxRetry  = asynq.MaxRetry
+	Queue     = asynq.Queue
+	Unique    = asynq.Unique
+	ProcessIn = asynq.ProcessIn
+)
+
+// Client enqueues tasks onto the Redis-backed queue.
+type Client struct {
+	client *asynq.Client
+}
+
+// NewClient returns a Client connected to the given Redis instance.
+func NewClient(redis asynq.RedisConnOpt) *Client {
+	return &Client{client: asynq.NewClient(redis)}
+}
+
+// Close releases the underlying Redis connection.
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// Enqueue JSON-encodes payload and schedules it as a task of the given
+// type, applying any Options (retry count, queue, delay, uniqueness, ...).
+func (c *Client) Enqueue(taskType string, payload interface{}, opts ...Option) (*asynq.TaskInfo, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Enqueue(asynq.NewTask(taskType, data), opts...)
+}
+
+// Server processes tasks registered on its mux, pulling from whichever
+// queues it was configured with.
+type Server struct {
+	srv *asynq.Server
+	mux *asynq.ServeMux
+}
+
+// NewServer returns a Server that pulls from queues according to their
+// relative priority, e.g. QueueCritical ahead of QueueCrawl.
+func NewServer(redis asynq.RedisConnOpt, queues map[string]int) *Server {
+	return &Server{
+		srv: asynq.NewServer(redis, asynq.Config{Queues: queues}),
+		mux: asynq.NewServeMux(),
+	}
+}
+
+// HandleFunc registers a handler for the given task type.
+func (s *Server) HandleFunc(taskType string, fn func(ctx context.Context, t *asynq.Task) error) {
+	s.mux.HandleFunc(taskType, fn)
+}
+
+// Run starts processing tasks until the process receives a shutdown signal.
+func (s *Server) Run() error {
+	return s.srv.Run(s.mux)
+}
+
+// NewInspector returns an asynq.Inspector for the given Redis instance, used
+// to report queue depth and failures (see AdminHandler).
+func NewInspector(redis asynq.RedisConnOpt) *asynq.Inspector {
+	return asynq.NewInspector(redis)
+}
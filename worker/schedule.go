@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cjlucas/unnamedcast/worker/queue"
+)
+
+// RefreshInterval is how often a known feed is re-crawled for new items,
+// independent of any itunes:crawl-genre discovery traffic.
+const RefreshInterval = 6 * time.Hour
+
+// runRefreshScheduler calls ScheduleRefresh once per RefreshInterval until
+// the process exits.
+func runRefreshScheduler(q *queue.Client, source feedSource) {
+	ticker := time.NewTicker(RefreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := ScheduleRefresh(q, source); err != nil {
+			log.Printf("worker: scheduled refresh failed: %v", err)
+		}
+	}
+}
+
+// ScheduleRefresh enqueues a feed:update task for every feed known to
+// source on QueueDefault, delayed by RefreshInterval via queue.ProcessIn.
+// Every task from one call gets the same delay, so they all become ready
+// to run at the same time.
+func ScheduleRefresh(q *queue.Client, source feedSource) error {
+	feeds, err := source.Feeds()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range feeds {
+		payload := updateFeedPayload{FeedID: f.ID, URL: f.URL}
+		opts := []queue.Option{
+			queue.ProcessIn(RefreshInterval),
+			queue.Queue(queue.QueueDefault),
+		}
+		if _, err := q.Enqueue(queue.TypeFeedUpdate, payload, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TriggerUpdate enqueues an immediate feed:update task on QueueCritical, so
+// a user explicitly requesting a refresh (e.g. pull-to-refresh) jumps ahead
+// of both background crawl traffic and ScheduleRefresh's own delayed tasks.
+func TriggerUpdate(q *queue.Client, feedID, url string) error {
+	payload := updateFeedPayload{FeedID: feedID, URL: url}
+	_, err := q.Enqueue(queue.TypeFeedUpdate, payload, queue.Queue(queue.QueueCritical))
+	return err
+}
+
+// refreshRequest is the body of a user-triggered POST /feeds/refresh call.
+type refreshRequest struct {
+	FeedID string `json:"feed_id"`
+	URL    string `json:"url"`
+}
+
+// refreshHandler adapts TriggerUpdate to an HTTP endpoint clients can call
+// directly, e.g. from a pull-to-refresh action in the app.
+func refreshHandler(q *queue.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req refreshRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := TriggerUpdate(q, req.FeedID, req.URL); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
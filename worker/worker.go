@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cjlucas/unnamedcast/worker/api"
+	"github.com/cjlucas/unnamedcast/worker/queue"
+	"github.com/hibiken/asynq"
+)
+
+// feedSource is the subset of the API client the worker needs: loading a
+// feed by ID or source URL, listing every known feed, fetching a feed's
+// current state by re-crawling its URL, and persisting a feed back once
+// it's been merged.
+type feedSource interface {
+	FeedByID(id string) (*api.Feed, error)
+	FeedByURL(url string) (*api.Feed, error)
+	Feeds() ([]api.Feed, error)
+	FetchFeed(url string) (*api.Feed, error)
+	SaveFeed(feed *api.Feed) error
+}
+
+// UpdateFeedWorker fetches the latest version of a feed and merges any new
+// or changed items into the stored copy. Its handlers are registered on
+// the worker's queue.Server by RegisterHandlers rather than being invoked
+// directly.
+type UpdateFeedWorker struct {
+	Source feedSource
+
+	// queue is set by RegisterHandlers so HandleUpdate can enqueue the
+	// feed:merge task it hands off to.
+	queue *queue.Client
+}
+
+type updateFeedPayload struct {
+	FeedID string `json:"feed_id"`
+	URL    string `json:"url"`
+}
+
+// HandleUpdate decodes an updateFeedPayload, fetches the feed's current
+// state from its source URL, and enqueues a feed:merge task to combine it
+// with whatever's already stored. The fetch and the merge are split across
+// two tasks so a slow crawl doesn't hold up the queue worker that performs
+// the (fast, synchronous) merge and write.
+func (w UpdateFeedWorker) HandleUpdate(ctx context.Context, t *asynq.Task) error {
+	var p updateFeedPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return err
+	}
+
+	oldFeed, err := w.Source.FeedByURL(p.URL)
+	if err == api.ErrNotFound {
+		oldFeed = &api.Feed{ID: p.FeedID, URL: p.URL}
+	} else if err != nil {
+		return err
+	}
+
+	newFeed, err := w.Source.FetchFeed(p.URL)
+	if err != nil {
+		return err
+	}
+
+	return w.enqueueMerge(oldFeed, newFeed)
+}
+
+type mergeFeedPayload struct {
+	Old api.Feed `json:"old"`
+	New api.Feed `json:"new"`
+}
+
+func (w UpdateFeedWorker) enqueueMerge(oldFeed, newFeed *api.Feed) error {
+	_, err := w.queue.Enqueue(queue.TypeFeedMerge, mergeFeedPayload{Old: *oldFeed, New: *newFeed})
+	return err
+}
+
+// HandleMerge decodes a mergeFeedPayload and persists the merge of its two
+// feeds via Source.
+func (w UpdateFeedWorker) HandleMerge(ctx context.Context, t *asynq.Task) error {
+	var p mergeFeedPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return err
+	}
+
+	merged := w.mergeFeeds(&p.Old, &p.New)
+	merged.ID = p.Old.ID
+	merged.URL = p.New.URL
+
+	return w.Source.SaveFeed(merged)
+}
+
+// mergeFeeds merges newFeed into oldFeed, returning a feed whose Items is
+// the union of both feeds' items keyed by GUID. Where both feeds have an
+// item with the same GUID, newFeed's copy wins.
+func (w UpdateFeedWorker) mergeFeeds(oldFeed, newFeed *api.Feed) *api.Feed {
+	merged := make(map[string]api.Item)
+	for _, item := range oldFeed.Items {
+		merged[item.GUID] = item
+	}
+	for _, item := range newFeed.Items {
+		merged[item.GUID] = item
+	}
+
+	out := &api.Feed{Items: make([]api.Item, 0, len(merged))}
+	for _, item := range merged {
+		out.Items = append(out.Items, item)
+	}
+	return out
+}
+
+// RegisterHandlers wires UpdateFeedWorker and the iTunes crawler onto s's
+// mux, so feed:update, feed:merge, itunes:crawl-genre, and
+// itunes:resolve-feed tasks are all dispatched by the queue server rather
+// than handled in-process.
+func RegisterHandlers(s *queue.Server, q *queue.Client, w UpdateFeedWorker) {
+	w.queue = q
+
+	s.HandleFunc(queue.TypeFeedUpdate, w.HandleUpdate)
+	s.HandleFunc(queue.TypeFeedMerge, w.HandleMerge)
+	s.HandleFunc(queue.TypeItunesCrawlGenre, func(ctx context.Context, t *asynq.Task) error {
+		var p crawlGenrePayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return err
+		}
+		return crawlGenre(q, p.URL)
+	})
+	s.HandleFunc(queue.TypeItunesResolveFeed, func(ctx context.Context, t *asynq.Task) error {
+		var p resolveFeedPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return err
+		}
+		return resolveFeed(q, p.URL)
+	})
+}